@@ -0,0 +1,234 @@
+package ghpr
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/go-github/github"
+	"golang.org/x/oauth2"
+)
+
+// AuthKind identifies one of the authentication modes an AuthMethod carries.
+type AuthKind int
+
+const (
+	// AuthUsernamePassword authenticates with a plain username/password (or
+	// username/PAT) pair over HTTP basic auth. This is the mode Credentials
+	// has always implied.
+	AuthUsernamePassword AuthKind = iota
+	// AuthAccessToken authenticates with a bearer-style token over HTTP,
+	// sent as the password half of basic auth with a conventional username.
+	AuthAccessToken
+	// AuthSSHKey authenticates the git transport with a private key file on
+	// disk, optionally passphrase-protected.
+	AuthSSHKey
+	// AuthSSHAgent authenticates the git transport via a running ssh-agent.
+	AuthSSHAgent
+	// AuthAnonymous performs no authentication at all, for public
+	// repositories that allow anonymous clone.
+	AuthAnonymous
+	// AuthGithubApp authenticates as a GitHub App installation, minting
+	// short-lived installation tokens from a JWT signed with the app's
+	// private key.
+	AuthGithubApp
+)
+
+// AuthMethod describes how a Forge should authenticate, both for the git
+// transport (clone/push) and, where applicable, the hosting provider's REST
+// API. Construct one with the matching helper (UsernamePasswordAuth,
+// AccessTokenAuth, SSHKeyAuth, SSHAgentAuth, AnonymousAuth or GithubAppAuth)
+// rather than filling in the struct directly.
+type AuthMethod struct {
+	Kind AuthKind
+
+	// Username and Token back AuthUsernamePassword and AuthAccessToken.
+	Username string
+	Token    string
+
+	// SSHKeyPath, SSHKeyPassphrase and SSHUser back AuthSSHKey and
+	// AuthSSHAgent. SSHUser defaults to "git" when empty.
+	SSHKeyPath       string
+	SSHKeyPassphrase string
+	SSHUser          string
+
+	// AppID, InstallationID and PrivateKeyPath back AuthGithubApp.
+	AppID          int64
+	InstallationID int64
+	PrivateKeyPath string
+
+	// appTokens caches and refreshes the installation token for
+	// AuthGithubApp. It is populated by GithubAppAuth so that repeated
+	// transportAuth/githubRESTClient calls share one refreshing source
+	// instead of re-minting a token (and re-reading the private key) every
+	// time.
+	appTokens *appInstallationTokenSource
+}
+
+// UsernamePasswordAuth authenticates with a username and password (or a
+// classic PAT passed as the password).
+func UsernamePasswordAuth(username, password string) AuthMethod {
+	return AuthMethod{Kind: AuthUsernamePassword, Username: username, Token: password}
+}
+
+// AccessTokenAuth authenticates with a bearer token, such as a fine-grained
+// GitHub PAT or GitLab/Gitea access token.
+func AccessTokenAuth(token string) AuthMethod {
+	return AuthMethod{Kind: AuthAccessToken, Token: token}
+}
+
+// SSHKeyAuth authenticates the git transport with the private key at
+// keyPath, decrypting it with passphrase if non-empty.
+func SSHKeyAuth(keyPath string, passphrase string) AuthMethod {
+	return AuthMethod{Kind: AuthSSHKey, SSHKeyPath: keyPath, SSHKeyPassphrase: passphrase}
+}
+
+// SSHAgentAuth authenticates the git transport via a running ssh-agent,
+// identified by the SSH_AUTH_SOCK environment variable.
+func SSHAgentAuth() AuthMethod {
+	return AuthMethod{Kind: AuthSSHAgent}
+}
+
+// AnonymousAuth performs no authentication. It only works against public
+// repositories that allow anonymous clone.
+func AnonymousAuth() AuthMethod {
+	return AuthMethod{Kind: AuthAnonymous}
+}
+
+// GithubAppAuth authenticates as the installation installationID of the
+// GitHub App appID, signing JWTs with the PEM-encoded private key at
+// privateKeyPath and exchanging them for short-lived installation tokens
+// that are refreshed automatically as they near expiry.
+func GithubAppAuth(appID int64, installationID int64, privateKeyPath string) AuthMethod {
+	return AuthMethod{
+		Kind:           AuthGithubApp,
+		AppID:          appID,
+		InstallationID: installationID,
+		PrivateKeyPath: privateKeyPath,
+		appTokens: &appInstallationTokenSource{
+			appID:          appID,
+			installationID: installationID,
+			privateKeyPath: privateKeyPath,
+		},
+	}
+}
+
+// toAuthMethod converts legacy Credentials into the equivalent
+// AuthUsernamePassword AuthMethod, preserving the module's historical
+// behavior of wiring Username/Token straight into HTTP basic auth.
+func (c Credentials) toAuthMethod() AuthMethod {
+	return UsernamePasswordAuth(c.Username, c.Token)
+}
+
+// transportAuth builds the go-git transport.AuthMethod corresponding to a,
+// minting a fresh GitHub App installation token first if necessary. It
+// returns (nil, nil) for AuthAnonymous, which go-git treats as "no auth".
+func (a AuthMethod) transportAuth() (transport.AuthMethod, error) {
+	sshUser := a.SSHUser
+	if sshUser == "" {
+		sshUser = "git"
+	}
+
+	switch a.Kind {
+	case AuthUsernamePassword:
+		return &githttp.BasicAuth{Username: a.Username, Password: a.Token}, nil
+	case AuthAccessToken:
+		return &githttp.BasicAuth{Username: "x-access-token", Password: a.Token}, nil
+	case AuthSSHKey:
+		return ssh.NewPublicKeysFromFile(sshUser, a.SSHKeyPath, a.SSHKeyPassphrase)
+	case AuthSSHAgent:
+		return ssh.NewSSHAgentAuth(sshUser)
+	case AuthAnonymous:
+		return nil, nil
+	case AuthGithubApp:
+		tok, err := a.appTokens.Token()
+		if err != nil {
+			return nil, err
+		}
+		return &githttp.BasicAuth{Username: "x-access-token", Password: tok.AccessToken}, nil
+	default:
+		return nil, fmt.Errorf("ghpr: unknown auth kind %d", a.Kind)
+	}
+}
+
+// githubRESTClient builds the go-github client backing a GithubPR's REST
+// calls, refreshing GitHub App installation tokens on demand.
+func (a AuthMethod) githubRESTClient() *github.Client {
+	if a.Kind == AuthGithubApp {
+		return github.NewClient(oauth2.NewClient(context.Background(), a.appTokens))
+	}
+	if a.Token == "" {
+		return github.NewClient(nil)
+	}
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: a.Token})
+	return github.NewClient(oauth2.NewClient(context.Background(), ts))
+}
+
+// appInstallationTokenSource mints and caches a GitHub App installation
+// access token, re-minting it a minute before it expires. It implements
+// oauth2.TokenSource so it can back an oauth2.NewClient directly.
+type appInstallationTokenSource struct {
+	appID          int64
+	installationID int64
+	privateKeyPath string
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// Token returns the cached installation token, minting a new one if none is
+// cached or the cached one is about to expire.
+func (s *appInstallationTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Now().Before(s.expiresAt.Add(-time.Minute)) {
+		return &oauth2.Token{AccessToken: s.token, Expiry: s.expiresAt}, nil
+	}
+
+	appJWT, err := s.signAppJWT()
+	if err != nil {
+		return nil, err
+	}
+
+	jwtClient := oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(&oauth2.Token{AccessToken: appJWT, TokenType: "Bearer"}))
+	installTok, _, err := github.NewClient(jwtClient).Apps.CreateInstallationToken(context.Background(), s.installationID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s.token = installTok.GetToken()
+	s.expiresAt = installTok.GetExpiresAt()
+
+	return &oauth2.Token{AccessToken: s.token, Expiry: s.expiresAt}, nil
+}
+
+// signAppJWT signs a short-lived (10 minute) JWT identifying the app,
+// as required by GitHub's "authenticating as a GitHub App" flow.
+func (s *appInstallationTokenSource) signAppJWT() (string, error) {
+	keyBytes, err := ioutil.ReadFile(s.privateKeyPath)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(keyBytes)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := jwt.RegisteredClaims{
+		IssuedAt:  jwt.NewNumericDate(now.Add(-time.Minute)),
+		ExpiresAt: jwt.NewNumericDate(now.Add(9 * time.Minute)),
+		Issuer:    fmt.Sprintf("%d", s.appID),
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(key)
+}
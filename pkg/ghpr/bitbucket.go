@@ -0,0 +1,178 @@
+package ghpr
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/ktrysmt/go-bitbucket"
+)
+
+// BitbucketPR is a Forge implementation backed by Bitbucket Cloud pull
+// requests and build statuses.
+type BitbucketPR struct {
+	base
+	bitbucketClient *bitbucket.Client
+	pr              int
+}
+
+// MakeBitbucketPR creates a new BitbucketPR with all the necessary state to
+// clone, commit, raise a PR and merge.
+func MakeBitbucketPR(repoName string, creds Credentials, opts ...Option) (*BitbucketPR, error) {
+	return MakeBitbucketPRWithAuth(repoName, creds.toAuthMethod(), opts...)
+}
+
+// MakeBitbucketPRWithAuth creates a new BitbucketPR authenticating with
+// auth, for callers that need SSH-key, SSH-agent or anonymous git transport
+// instead of a plain username/password.
+func MakeBitbucketPRWithAuth(repoName string, auth AuthMethod, opts ...Option) (*BitbucketPR, error) {
+	fs := osfs.New(".")
+	return makeBitbucketPR(repoName, auth, &fs, realGoGit{}, opts...)
+}
+
+func makeBitbucketPR(repoName string, auth AuthMethod, fs *billy.Filesystem, gogit goGit, opts ...Option) (*BitbucketPR, error) {
+	b, err := newBase(repoName, auth, fs, gogit, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BitbucketPR{
+		base:            b,
+		bitbucketClient: bitbucket.NewBasicAuth(auth.Username, auth.Token),
+	}, nil
+}
+
+// Clone shallow clones the Bitbucket repository
+func (b *BitbucketPR) Clone(ctx context.Context) error {
+	url := fmt.Sprintf("https://bitbucket.org/%s/%s.git", b.owner, b.repo)
+	return b.base.clone(ctx, url)
+}
+
+// PushCommit creates a commit for the Worktree changes made by the
+// UpdateFunc parameter and pushes that branch to the remote origin server
+func (b *BitbucketPR) PushCommit(ctx context.Context, branchName string, fn UpdateFunc) error {
+	return b.base.pushCommit(ctx, branchName, fn)
+}
+
+// RaisePR opens a Bitbucket pull request from sourceBranch (HEAD) to
+// targetBranch (base). The go-bitbucket client does not accept a context per
+// call, so ctx is accepted here only for parity with the Forge interface.
+func (b *BitbucketPR) RaisePR(ctx context.Context, sourceBranch string, targetBranch string, title string, body string) error {
+	opt := &bitbucket.PullRequestsOptions{
+		Owner:             b.owner,
+		RepoSlug:          b.repo,
+		Title:             title,
+		Description:       body,
+		SourceBranch:      sourceBranch,
+		DestinationBranch: targetBranch,
+	}
+
+	pr, err := b.bitbucketClient.Repositories.PullRequests.Create(opt)
+	if err != nil {
+		return err
+	}
+
+	id, err := pullRequestID(pr)
+	if err != nil {
+		return err
+	}
+	b.pr = id
+	return nil
+}
+
+// GetPRStatus returns the aggregate build status of the PR's source commit.
+func (b *BitbucketPR) GetPRStatus(ctx context.Context) (string, error) {
+	statuses, err := b.ListStatuses(ctx, "")
+	if err != nil {
+		return "", err
+	}
+	if len(statuses) == 0 {
+		// No builds have reported a status yet; this is not the same as
+		// every build having succeeded, so don't default to SUCCESSFUL.
+		return "PENDING", nil
+	}
+	for _, s := range statuses {
+		if s.State != "SUCCESSFUL" {
+			return s.State, nil
+		}
+	}
+	return "SUCCESSFUL", nil
+}
+
+// ListStatuses returns the individual build statuses of the PR's current
+// source commit, read from the PR's own statuses resource. sha is not used:
+// statuses of any other commit cannot be listed through this method.
+func (b *BitbucketPR) ListStatuses(ctx context.Context, sha string) ([]Status, error) {
+	opt := &bitbucket.PullRequestsOptions{
+		Owner:    b.owner,
+		RepoSlug: b.repo,
+		ID:       fmt.Sprintf("%d", b.pr),
+	}
+
+	raw, err := b.bitbucketClient.Repositories.PullRequests.GetStatuses(opt)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseBitbucketStatuses(raw)
+}
+
+// Merge merges the PR. Bitbucket's merge strategies are named "merge_commit",
+// "squash" and "fast_forward"; method is passed through verbatim.
+func (b *BitbucketPR) Merge(ctx context.Context, sha string, method string) error {
+	opt := &bitbucket.PullRequestsOptions{
+		Owner:         b.owner,
+		RepoSlug:      b.repo,
+		ID:            fmt.Sprintf("%d", b.pr),
+		MergeStrategy: method,
+	}
+
+	_, err := b.bitbucketClient.Repositories.PullRequests.Merge(opt)
+	return err
+}
+
+// Close removes the cloned repository from the filesystem
+func (b *BitbucketPR) Close() error {
+	return b.base.close()
+}
+
+// pullRequestID extracts the numeric PR id from the generic map the
+// go-bitbucket client decodes API responses into.
+func pullRequestID(raw interface{}) (int, error) {
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return 0, fmt.Errorf("unexpected pull request response shape: %T", raw)
+	}
+	id, ok := m["id"].(float64)
+	if !ok {
+		return 0, fmt.Errorf("pull request response missing id")
+	}
+	return int(id), nil
+}
+
+// parseBitbucketStatuses converts the generic "values" array the
+// go-bitbucket client decodes the build-statuses response into our shared
+// Status type.
+func parseBitbucketStatuses(raw interface{}) ([]Status, error) {
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected statuses response shape: %T", raw)
+	}
+	values, ok := m["values"].([]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	result := make([]Status, 0, len(values))
+	for _, v := range values {
+		entry, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		key, _ := entry["key"].(string)
+		state, _ := entry["state"].(string)
+		result = append(result, Status{Context: key, State: state})
+	}
+	return result, nil
+}
@@ -0,0 +1,245 @@
+package ghpr
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/gofrs/flock"
+)
+
+// Default cache behavior used by Cache when a field is left zero.
+const (
+	DefaultCacheDir   = ".ghpr-cache"
+	DefaultPruneAfter = 7 * 24 * time.Hour
+)
+
+// Cache is a shared, on-disk store of bare mirror clones keyed by
+// "owner/repo". Passing one to MakeGithubPRWithCache lets repeated runs
+// against the same repository fetch incrementally instead of paying for a
+// full clone every time. Only refs/heads/* is mirrored, which is all this
+// package's clone-commit-push-PR flow needs.
+type Cache struct {
+	// Dir is the root directory mirrors are stored under, one subdirectory
+	// per owner and one "<repo>.git" bare repository per repo. Empty means
+	// DefaultCacheDir.
+	Dir string
+	// PruneAfter removes a mirror that hasn't been fetched in this long the
+	// next time Prune runs. Zero means DefaultPruneAfter; negative disables
+	// pruning entirely.
+	PruneAfter time.Duration
+}
+
+// NewCache returns a Cache rooted at dir, using the default prune policy.
+func NewCache(dir string) *Cache {
+	return &Cache{Dir: dir}
+}
+
+func (c *Cache) dir() string {
+	if c.Dir == "" {
+		return DefaultCacheDir
+	}
+	return c.Dir
+}
+
+func (c *Cache) pruneAfter() time.Duration {
+	if c.PruneAfter == 0 {
+		return DefaultPruneAfter
+	}
+	return c.PruneAfter
+}
+
+// mirrorPath is the on-disk location of owner/repo's bare mirror.
+func (c *Cache) mirrorPath(owner, repo string) string {
+	return filepath.Join(c.dir(), owner, repo+".git")
+}
+
+// lock serializes access to owner/repo's mirror, including from other
+// processes sharing the same cache directory, via a lock file alongside the
+// mirror. The caller must call the returned func to release it.
+func (c *Cache) lock(owner, repo string) (func(), error) {
+	if err := os.MkdirAll(filepath.Join(c.dir(), owner), 0o755); err != nil {
+		return nil, err
+	}
+
+	fl := flock.New(c.mirrorPath(owner, repo) + ".lock")
+	if err := fl.Lock(); err != nil {
+		return nil, err
+	}
+	return func() { fl.Unlock() }, nil
+}
+
+// mirror brings owner/repo's bare mirror up to date with url, creating it
+// from scratch on first use and re-creating it if an incremental fetch
+// against the existing mirror fails, and returns its path. Callers must hold
+// the lock returned by c.lock for the duration of this call.
+func (c *Cache) mirror(ctx context.Context, owner, repo, url string, auth transport.AuthMethod) (string, error) {
+	path := c.mirrorPath(owner, repo)
+
+	repoStore, err := git.PlainOpen(path)
+	if err != nil {
+		return path, c.cloneMirror(ctx, path, url, auth)
+	}
+
+	remote, err := repoStore.Remote("origin")
+	if err != nil {
+		return path, c.cloneMirror(ctx, path, url, auth)
+	}
+
+	err = remote.FetchContext(ctx, &git.FetchOptions{
+		Auth:     auth,
+		RefSpecs: []config.RefSpec{"+refs/heads/*:refs/heads/*"},
+		Force:    true,
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		// The mirror may be corrupt; fall back to recreating it rather than
+		// failing the whole clone.
+		return path, c.cloneMirror(ctx, path, url, auth)
+	}
+
+	// Prune ages mirrors by their directory's modification time, which a
+	// fetch does not otherwise update.
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		return path, err
+	}
+
+	return path, nil
+}
+
+// cloneMirror (re)creates owner/repo's bare mirror from scratch at path.
+func (c *Cache) cloneMirror(ctx context.Context, path string, url string, auth transport.AuthMethod) error {
+	if err := os.RemoveAll(path); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	_, err := git.PlainCloneContext(ctx, path, true, &git.CloneOptions{
+		URL:  url,
+		Auth: auth,
+	})
+	return err
+}
+
+// Prune removes mirrors that haven't been fetched in more than
+// c.pruneAfter(). It is not called automatically; callers with long-running
+// or frequently-invoked processes should schedule it themselves.
+func (c *Cache) Prune() error {
+	if c.pruneAfter() < 0 {
+		return nil
+	}
+
+	cutoff := time.Now().Add(-c.pruneAfter())
+	owners, err := os.ReadDir(c.dir())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, owner := range owners {
+		if !owner.IsDir() {
+			continue
+		}
+		ownerDir := filepath.Join(c.dir(), owner.Name())
+		repos, err := os.ReadDir(ownerDir)
+		if err != nil {
+			return err
+		}
+		for _, repo := range repos {
+			if filepath.Ext(repo.Name()) != ".git" {
+				continue
+			}
+			info, err := repo.Info()
+			if err != nil {
+				return err
+			}
+			if info.ModTime().Before(cutoff) {
+				if err := os.RemoveAll(filepath.Join(ownerDir, repo.Name())); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// cloneFromCache populates b.gitRepo from b.cache's mirror of url instead of
+// performing a fresh clone: it brings the mirror up to date, then clones
+// this run's own working tree from the local mirror path, so only the
+// incremental fetch costs network time.
+//
+// The clone deliberately does not open the mirror's own storer as this
+// run's gitRepo: the mirror is shared across every concurrent run against
+// the same owner/repo, so writing pushCommit's new branch ref and commit
+// objects there would race other runs and leave stray branches behind it.
+// Giving each run its own storer, populated via a local (network-free)
+// clone of the mirror, keeps the mirror read-only outside of mirror's own
+// fetch. The cache lock is held for the whole call, including this local
+// clone, since it reads the mirror that mirror's fetch writes.
+func (b *base) cloneFromCache(ctx context.Context, url string) error {
+	transportAuth, err := b.authMethod.transportAuth()
+	if err != nil {
+		return err
+	}
+
+	unlock, err := b.cache.lock(b.owner, b.repo)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	mirrorPath, err := b.cache.mirror(ctx, b.owner, b.repo, url, transportAuth)
+	if err != nil {
+		return err
+	}
+
+	storageWorkTree, err := b.filesystem.Chroot(".git")
+	if err != nil {
+		return err
+	}
+
+	gitRepo, err := b.git.Clone(
+		ctx,
+		filesystem.NewStorage(storageWorkTree, cache.NewObjectLRUDefault()),
+		b.filesystem,
+		&git.CloneOptions{URL: mirrorPath},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Cloning from mirrorPath leaves "origin" pointed at the local mirror on
+	// disk. pushCommit pushes to "origin" with no override, so it must be
+	// repointed at the real remote url here, otherwise the PR branch is
+	// pushed into the shared mirror instead of the forge, and never reaches
+	// it.
+	if err := repointOrigin(gitRepo, url); err != nil {
+		return err
+	}
+
+	b.gitRepo = gitRepo
+	return nil
+}
+
+// repointOrigin rewrites gitRepo's "origin" remote to url, replacing
+// whatever URL the clone that produced gitRepo set it to.
+func repointOrigin(gitRepo *git.Repository, url string) error {
+	if err := gitRepo.DeleteRemote("origin"); err != nil {
+		return err
+	}
+	_, err := gitRepo.CreateRemote(&config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{url},
+	})
+	return err
+}
@@ -0,0 +1,85 @@
+package ghpr
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// TestCloneFromCachePointsOriginAtRealRemote guards against a cached clone's
+// "origin" remote being left pointed at the shared mirror on disk: pushCommit
+// pushes to "origin" with no override, so if cloneFromCache didn't repoint it
+// at the real forge, every cached PushCommit would push into the mirror
+// instead of the remote, and RaisePR would then reference a branch that
+// never reached it.
+func TestCloneFromCachePointsOriginAtRealRemote(t *testing.T) {
+	dir := t.TempDir()
+
+	remotePath := filepath.Join(dir, "remote.git")
+	seedBareRepo(t, remotePath)
+
+	var fs billy.Filesystem = osfs.New(filepath.Join(dir, "work"))
+	b, err := newBase("owner/repo", AnonymousAuth(), &fs, realGoGit{})
+	if err != nil {
+		t.Fatalf("newBase: %v", err)
+	}
+	b.cache = NewCache(filepath.Join(dir, "cache"))
+
+	if err := b.cloneFromCache(context.Background(), remotePath); err != nil {
+		t.Fatalf("cloneFromCache: %v", err)
+	}
+
+	origin, err := b.gitRepo.Remote("origin")
+	if err != nil {
+		t.Fatalf("Remote(origin): %v", err)
+	}
+
+	urls := origin.Config().URLs
+	if len(urls) != 1 || urls[0] != remotePath {
+		t.Fatalf("origin URLs = %v, want [%s] (the real remote, not the mirror)", urls, remotePath)
+	}
+
+	mirrorPath := b.cache.mirrorPath(b.owner, b.repo)
+	if urls[0] == mirrorPath {
+		t.Fatalf("origin still points at the shared mirror %s", mirrorPath)
+	}
+}
+
+// seedBareRepo creates a bare git repository at path containing a single
+// commit, standing in for a real forge's remote.
+func seedBareRepo(t *testing.T, path string) {
+	t.Helper()
+
+	scratch := t.TempDir()
+	repo, err := git.PlainInit(scratch, false)
+	if err != nil {
+		t.Fatalf("PlainInit: %v", err)
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Worktree: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(scratch, "README.md"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if _, err := w.Add("README.md"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if _, err := w.Commit("initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	}); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	if _, err := git.PlainClone(path, true, &git.CloneOptions{URL: scratch}); err != nil {
+		t.Fatalf("PlainClone bare: %v", err)
+	}
+}
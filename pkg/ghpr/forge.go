@@ -0,0 +1,95 @@
+package ghpr
+
+import (
+	"context"
+	"fmt"
+)
+
+// Forge is the interface implemented by each supported Git hosting provider.
+// It captures the clone/commit/pull-request lifecycle that GithubPR
+// previously hard-wired directly against go-github, so callers can drive
+// GitHub, GitLab, Gitea or Bitbucket interchangeably through a value
+// obtained from Make, without type-asserting back to a concrete *GithubPR,
+// *GitlabPR, *GiteaPR or *BitbucketPR.
+type Forge interface {
+	// Clone clones the repository into the Forge's working directory.
+	Clone(ctx context.Context) error
+
+	// PushCommit creates a commit for the Worktree changes made by fn and
+	// pushes that branch to the remote origin server.
+	PushCommit(ctx context.Context, branchName string, fn UpdateFunc) error
+
+	// RaisePR creates a pull/merge request from sourceBranch (HEAD) to
+	// targetBranch (base).
+	RaisePR(ctx context.Context, sourceBranch string, targetBranch string, title string, body string) error
+
+	// GetPRStatus returns the aggregate status of the PR's head commit.
+	GetPRStatus(ctx context.Context) (string, error)
+
+	// ListStatuses returns the individual status/pipeline contexts recorded
+	// against sha.
+	ListStatuses(ctx context.Context, sha string) ([]Status, error)
+
+	// Merge merges the PR using the given merge method, provided it is in a
+	// mergeable state.
+	Merge(ctx context.Context, sha string, method string) error
+
+	// Close removes the cloned repository from the filesystem.
+	Close() error
+}
+
+// Waiter is implemented by Forges that can poll a PR's head commit (or a
+// merge commit) for individual status/check contexts to succeed before
+// proceeding, rather than only reporting an aggregate GetPRStatus. GithubPR
+// is the only Forge that implements it today, since it is also the only one
+// with GitHub Checks API support wired in; callers that need to wait on a
+// GitLab/Gitea/Bitbucket Forge should poll GetPRStatus themselves.
+type Waiter interface {
+	// WaitForPR waits until the raised PR's head commit satisfies opts.
+	WaitForPR(ctx context.Context, opts WaitOptions) error
+
+	// WaitForMergeCommit waits until the merge commit satisfies opts.
+	WaitForMergeCommit(ctx context.Context, opts WaitOptions) error
+}
+
+// ForgeKind identifies which Git hosting provider a Forge should talk to.
+type ForgeKind int
+
+const (
+	// ForgeGithub talks to github.com only; GitHub Enterprise instances are
+	// not supported.
+	ForgeGithub ForgeKind = iota
+	// ForgeGitlab talks to gitlab.com only; self-hosted GitLab instances are
+	// not supported.
+	ForgeGitlab
+	// ForgeGitea talks to a self-hosted Gitea instance.
+	ForgeGitea
+	// ForgeBitbucket talks to bitbucket.org (Bitbucket Cloud) only; Bitbucket
+	// Server is not supported.
+	ForgeBitbucket
+)
+
+// Make creates a Forge of the given kind for repoName, authenticating with
+// creds. baseURL selects a self-hosted instance and is required for
+// ForgeGitea (which has no well-known default host); it is ignored for
+// ForgeGithub, ForgeGitlab and ForgeBitbucket, which always talk to their
+// public SaaS endpoints. Make is the provider-agnostic counterpart to
+// MakeGithubPR, which remains available for source compatibility and
+// continues to return a concrete *GithubPR.
+func Make(kind ForgeKind, baseURL string, repoName string, creds Credentials) (Forge, error) {
+	switch kind {
+	case ForgeGithub:
+		return MakeGithubPR(repoName, creds)
+	case ForgeGitlab:
+		return MakeGitlabPR(repoName, creds)
+	case ForgeGitea:
+		if baseURL == "" {
+			return nil, fmt.Errorf("ghpr: baseURL is required for ForgeGitea")
+		}
+		return MakeGiteaPR(baseURL, repoName, creds)
+	case ForgeBitbucket:
+		return MakeBitbucketPR(repoName, creds)
+	default:
+		return nil, fmt.Errorf("ghpr: unsupported forge kind %d", kind)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
 	"strings"
@@ -16,11 +17,9 @@ import (
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/cache"
 	"github.com/go-git/go-git/v5/plumbing/object"
-	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/go-git/go-git/v5/storage"
 	"github.com/go-git/go-git/v5/storage/filesystem"
 	"github.com/google/go-github/github"
-	"golang.org/x/oauth2"
 )
 
 // UpdateFunc is a callback function which should create a series of changes
@@ -40,53 +39,88 @@ type Author struct {
 	Email string
 }
 
-// GithubPR GitHubPR is a container for all necessary state
-type GithubPR struct {
-	auth         http.BasicAuth
-	filesystem   billy.Filesystem
-	git          goGit
-	gitHubClient *github.Client
-	mergeSHA     string
-	path         string
-	pr           int
-	gitRepo      *git.Repository
-	owner        string
-	repo         string
+// Status represents the state of a single commit status or check context,
+// normalized across the status/pipeline APIs of the supported Forge
+// implementations.
+type Status struct {
+	Context string
+	State   string
 }
 
 // goGit provides an interface for to go-git methods in use by this module
 // This is interface is not exported.
 type goGit interface {
-	Clone(s storage.Storer, worktree billy.Filesystem, o *git.CloneOptions) (*git.Repository, error)
+	Clone(ctx context.Context, s storage.Storer, worktree billy.Filesystem, o *git.CloneOptions) (*git.Repository, error)
 }
 
 // realGoGit is a go-git backed implementation of the GoGit interface
 type realGoGit struct {
 }
 
-func (ghpr realGoGit) Clone(s storage.Storer, worktree billy.Filesystem, o *git.CloneOptions) (*git.Repository, error) {
-	return git.Clone(s, worktree, o)
+func (ghpr realGoGit) Clone(ctx context.Context, s storage.Storer, worktree billy.Filesystem, o *git.CloneOptions) (*git.Repository, error) {
+	return git.CloneContext(ctx, s, worktree, o)
 }
 
-// MakeGithubPR creates a new GithubPR struct with all the necessary state to clone, commit, raise a PR
-// and merge. The repository will be cloned to a temporary directory in the current directory
-func MakeGithubPR(repoName string, creds Credentials) (*GithubPR, error) {
-	fs := osfs.New(".")
-	return makeGithubPR(repoName, creds, &fs, realGoGit{})
+// base holds the git transport state shared by every Forge implementation:
+// the working tree on disk, the credentials used to authenticate against the
+// remote, and the underlying go-git repository once cloned. Each concrete
+// Forge embeds a base and adds whatever REST/GraphQL client it needs to talk
+// to its hosting provider.
+type base struct {
+	authMethod AuthMethod
+	filesystem billy.Filesystem
+	git        goGit
+	path       string
+	gitRepo    *git.Repository
+	owner      string
+	repo       string
+	// baseURL is only set by GiteaPR, since Gitea has no single well-known
+	// host.
+	baseURL string
+	// signer, if set, signs every commit PushCommit creates.
+	signer Signer
+	// committer, if set, is recorded as the commit's committer identity,
+	// distinct from the Author returned by UpdateFunc.
+	committer *object.Signature
+	// cache, if set, backs clone with a shared local mirror instead of a
+	// fresh remote clone every time. See MakeGithubPRWithCache.
+	cache *Cache
 }
 
-// makeGithubPR is an internal function for creating a GithubPR instance. It allows injecting a mock filesystem
-// and go-git implementation
-func makeGithubPR(repoName string, creds Credentials, fs *billy.Filesystem, gogit goGit) (*GithubPR, error) {
+// Option configures optional behavior on a Forge at construction time, such
+// as commit signing. It composes with every Make*PR/Make*PRWithAuth
+// constructor.
+type Option func(*base)
+
+// WithSigner configures commits created by PushCommit to be signed by
+// signer, satisfying branch protection rules that require signatures.
+func WithSigner(signer Signer) Option {
+	return func(b *base) {
+		b.signer = signer
+	}
+}
+
+// WithCommitter sets a separate committer identity for commits created by
+// PushCommit. When unset, the committer defaults to match the commit's
+// Author, as go-git itself does.
+func WithCommitter(committer Author) Option {
+	return func(b *base) {
+		b.committer = &object.Signature{Name: committer.Name, Email: committer.Email}
+	}
+}
+
+// newBase validates repoName, reserves a temporary directory for the clone
+// and returns a base ready to have its remote URL populated by the caller.
+func newBase(repoName string, auth AuthMethod, fs *billy.Filesystem, gogit goGit, opts ...Option) (base, error) {
 	// A loose regex for a format of <user|org>/<repository>
 	// Match one or more non-slash characters, followed by a slash,
 	// followed by one or morer non-slash characters
 	matched, err := regexp.MatchString("^[^/]+/[^/]+$", repoName)
 	if err != nil {
-		return nil, err
+		return base{}, err
 	}
 	if !matched {
-		return nil, errors.New("invalid repository name supplied")
+		return base{}, errors.New("invalid repository name supplied")
 	}
 
 	owner := strings.Split(repoName, "/")[0]
@@ -94,71 +128,76 @@ func makeGithubPR(repoName string, creds Credentials, fs *billy.Filesystem, gogi
 
 	tempDir, err := util.TempDir(*fs, ".", "repo_")
 	if err != nil {
-		return nil, err
+		return base{}, err
 	}
 
 	*fs, err = (*fs).Chroot(tempDir)
 	if err != nil {
-		return nil, err
+		return base{}, err
 	}
 
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: creds.Token},
-	)
-	tc := oauth2.NewClient(context.Background(), ts)
+	b := base{
+		filesystem: *fs,
+		authMethod: auth,
+		path:       tempDir,
+		git:        gogit,
+		repo:       repo,
+		owner:      owner,
+	}
+	for _, opt := range opts {
+		opt(&b)
+	}
 
-	return &GithubPR{
-		filesystem:   *fs,
-		auth:         http.BasicAuth{Username: creds.Username, Password: creds.Token},
-		path:         tempDir,
-		gitHubClient: github.NewClient(tc),
-		git:          gogit,
-		repo:         repo,
-		owner:        owner,
-	}, nil
+	return b, nil
 }
 
-// Clone shallow clones the GitHub repository
-func (ghpr *GithubPR) Clone() error {
-	url := fmt.Sprintf("https://github.com/" + ghpr.owner + "/" + ghpr.repo)
+// clone populates the base's working tree from url, either with a fresh
+// shallow clone or, if b.cache is set, from a shared local mirror.
+func (b *base) clone(ctx context.Context, url string) error {
+	if b.cache != nil {
+		return b.cloneFromCache(ctx, url)
+	}
 
-	storageWorkTree, err := ghpr.filesystem.Chroot(".git")
+	transportAuth, err := b.authMethod.transportAuth()
+	if err != nil {
+		return err
+	}
+
+	storageWorkTree, err := b.filesystem.Chroot(".git")
 	if err != nil {
 		return err
 	}
 
 	// Pass a defafult LRU object cache, as per git.PlainClone's implementation
-	ghpr.gitRepo, err = ghpr.git.Clone(
+	var err2 error
+	b.gitRepo, err2 = b.git.Clone(
+		ctx,
 		filesystem.NewStorage(storageWorkTree, cache.NewObjectLRUDefault()),
-		ghpr.filesystem,
+		b.filesystem,
 		&git.CloneOptions{
 			Depth: 1,
 			URL:   url,
-			Auth:  &ghpr.auth})
+			Auth:  transportAuth})
 
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return err2
 }
 
-// PushCommit creates a commit for the Worktree changes made by the UpdateFunc parameter
-// and pushes that branch to the remote origin server
-func (ghpr *GithubPR) PushCommit(branchName string, fn UpdateFunc) error {
-	headRef, err := ghpr.gitRepo.Head()
+// pushCommit creates a commit for the Worktree changes made by fn and pushes
+// that branch to the remote origin server.
+func (b *base) pushCommit(ctx context.Context, branchName string, fn UpdateFunc) error {
+	headRef, err := b.gitRepo.Head()
 	if err != nil {
 		return err
 	}
 
 	branchRef := fmt.Sprintf("refs/heads/%s", branchName)
 	ref := plumbing.NewHashReference(plumbing.ReferenceName(branchRef), headRef.Hash())
-	err = ghpr.gitRepo.Storer.SetReference(ref)
+	err = b.gitRepo.Storer.SetReference(ref)
 	if err != nil {
 		return err
 	}
 
-	w, err := ghpr.gitRepo.Worktree()
+	w, err := b.gitRepo.Worktree()
 	if err != nil {
 		return err
 	}
@@ -175,27 +214,237 @@ func (ghpr *GithubPR) PushCommit(branchName string, fn UpdateFunc) error {
 		author.When = time.Now()
 	}
 
-	_, err = w.Commit(commitMessage, &git.CommitOptions{Author: author})
+	commitOpts := &git.CommitOptions{Author: author}
+	if b.committer != nil {
+		commitOpts.Committer = b.committer
+	}
+	if b.signer != nil {
+		commitOpts.SignKey = b.signer.openpgpEntity()
+	}
+
+	commitHash, err := w.Commit(commitMessage, commitOpts)
 	if err != nil {
 		return err
 	}
 
+	// go-git's CommitOptions.SignKey only supports OpenPGP. A Signer that
+	// returns no OpenPGP entity (e.g. SSHSigner) signs out-of-band here,
+	// rewriting the commit with its signature attached.
+	if b.signer != nil && commitOpts.SignKey == nil {
+		commitHash, err = b.signCommitOutOfBand(branchName, commitHash)
+		if err != nil {
+			return err
+		}
+	}
+
 	branchRef = fmt.Sprintf("refs/remotes/origin/%s", branchName)
-	ref = plumbing.NewHashReference(plumbing.ReferenceName(branchRef), headRef.Hash())
-	err = ghpr.gitRepo.Storer.SetReference(ref)
+	ref = plumbing.NewHashReference(plumbing.ReferenceName(branchRef), commitHash)
+	err = b.gitRepo.Storer.SetReference(ref)
+	if err != nil {
+		return err
+	}
+
+	transportAuth, err := b.authMethod.transportAuth()
 	if err != nil {
 		return err
 	}
 
-	err = ghpr.gitRepo.Push(&git.PushOptions{
-		Auth: &ghpr.auth,
+	return b.gitRepo.PushContext(ctx, &git.PushOptions{
+		Auth: transportAuth,
 	})
-	return err
+}
+
+// signCommitOutOfBand signs the commit at hash with b.signer's detached
+// signature, rewrites it into a new commit object carrying that signature,
+// and repoints branchName's local ref at the rewritten commit. It returns
+// the new commit's hash, which callers must use in place of hash from then
+// on since signing a commit changes its hash.
+func (b *base) signCommitOutOfBand(branchName string, hash plumbing.Hash) (plumbing.Hash, error) {
+	commit, err := b.gitRepo.CommitObject(hash)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	unsigned := &plumbing.MemoryObject{}
+	if err := commit.Encode(unsigned); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	reader, err := unsigned.Reader()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	payload, err := io.ReadAll(reader)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	signature, err := b.signer.signDetached(payload)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	commit.PGPSignature = signature
+
+	signed := &plumbing.MemoryObject{}
+	if err := commit.Encode(signed); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	newHash, err := b.gitRepo.Storer.SetEncodedObject(signed)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	branchRefName := plumbing.NewBranchReferenceName(branchName)
+	if err := b.gitRepo.Storer.SetReference(plumbing.NewHashReference(branchRefName, newHash)); err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	w, err := b.gitRepo.Worktree()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if err := w.Checkout(&git.CheckoutOptions{Hash: newHash, Force: true}); err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	return newHash, nil
+}
+
+// dryRunCommit runs fn's worktree changes and commits them locally, exactly
+// like pushCommit, but returns the diff against the branch's starting point
+// instead of pushing. It lets callers (namely Campaign's DryRun mode) preview
+// a fleet-wide change without touching the remote.
+func (b *base) dryRunCommit(branchName string, fn UpdateFunc) (string, error) {
+	headRef, err := b.gitRepo.Head()
+	if err != nil {
+		return "", err
+	}
+
+	branchRef := fmt.Sprintf("refs/heads/%s", branchName)
+	ref := plumbing.NewHashReference(plumbing.ReferenceName(branchRef), headRef.Hash())
+	if err := b.gitRepo.Storer.SetReference(ref); err != nil {
+		return "", err
+	}
+
+	w, err := b.gitRepo.Worktree()
+	if err != nil {
+		return "", err
+	}
+	w.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(branchName)})
+
+	commitMessage, author, err := fn(w)
+	if err != nil {
+		return "", err
+	}
+	if author.When.Equal(time.Time{}) {
+		author.When = time.Now()
+	}
+
+	commitHash, err := w.Commit(commitMessage, &git.CommitOptions{Author: author})
+	if err != nil {
+		return "", err
+	}
+
+	before, err := b.gitRepo.CommitObject(headRef.Hash())
+	if err != nil {
+		return "", err
+	}
+	after, err := b.gitRepo.CommitObject(commitHash)
+	if err != nil {
+		return "", err
+	}
+
+	patch, err := before.Patch(after)
+	if err != nil {
+		return "", err
+	}
+	return patch.String(), nil
+}
+
+// close removes the cloned repository from the filesystem.
+func (b *base) close() error {
+	return os.RemoveAll(b.path)
+}
+
+// GithubPR GitHubPR is a container for all necessary state
+type GithubPR struct {
+	base
+	gitHubClient *github.Client
+	mergeSHA     string
+	pr           int
+}
+
+// MakeGithubPR creates a new GithubPR struct with all the necessary state to clone, commit, raise a PR
+// and merge. The repository will be cloned to a temporary directory in the current directory. opts may
+// include WithSigner and WithCommitter to configure commit signing.
+func MakeGithubPR(repoName string, creds Credentials, opts ...Option) (*GithubPR, error) {
+	return MakeGithubPRWithAuth(repoName, creds.toAuthMethod(), opts...)
+}
+
+// MakeGithubPRWithAuth creates a new GithubPR authenticating with auth,
+// which may be any of the AuthMethod variants (username/password, access
+// token, SSH key, SSH agent, anonymous, or a GitHub App installation). This
+// is the entry point for CI bots that cannot use a long-lived PAT.
+func MakeGithubPRWithAuth(repoName string, auth AuthMethod, opts ...Option) (*GithubPR, error) {
+	fs := osfs.New(".")
+	return makeGithubPR(repoName, auth, &fs, realGoGit{}, opts...)
+}
+
+// makeGithubPR is an internal function for creating a GithubPR instance. It allows injecting a mock filesystem
+// and go-git implementation
+func makeGithubPR(repoName string, auth AuthMethod, fs *billy.Filesystem, gogit goGit, opts ...Option) (*GithubPR, error) {
+	b, err := newBase(repoName, auth, fs, gogit, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GithubPR{
+		base:         b,
+		gitHubClient: auth.githubRESTClient(),
+	}, nil
+}
+
+// MakeGithubPRWithCache creates a new GithubPR exactly like MakeGithubPR,
+// except that Clone populates its working tree from c's shared local mirror
+// instead of performing a fresh remote clone, fetching only what has changed
+// since the mirror's last use.
+func MakeGithubPRWithCache(c *Cache, repoName string, creds Credentials, opts ...Option) (*GithubPR, error) {
+	return MakeGithubPRWithCacheAndAuth(c, repoName, creds.toAuthMethod(), opts...)
+}
+
+// MakeGithubPRWithCacheAndAuth is MakeGithubPRWithCache for callers that need
+// an AuthMethod other than a plain username/password.
+func MakeGithubPRWithCacheAndAuth(c *Cache, repoName string, auth AuthMethod, opts ...Option) (*GithubPR, error) {
+	fs := osfs.New(".")
+	return makeGithubPRWithCache(c, repoName, auth, &fs, realGoGit{}, opts...)
+}
+
+func makeGithubPRWithCache(c *Cache, repoName string, auth AuthMethod, fs *billy.Filesystem, gogit goGit, opts ...Option) (*GithubPR, error) {
+	ghpr, err := makeGithubPR(repoName, auth, fs, gogit, opts...)
+	if err != nil {
+		return nil, err
+	}
+	ghpr.cache = c
+	return ghpr, nil
+}
+
+// Clone shallow clones the GitHub repository, or, for a GithubPR made with
+// MakeGithubPRWithCache, clones it from the cache's local mirror after
+// fetching into that mirror. ctx governs the clone RPC and
+// is checked for cancellation throughout the transfer.
+func (ghpr *GithubPR) Clone(ctx context.Context) error {
+	url := fmt.Sprintf("https://github.com/" + ghpr.owner + "/" + ghpr.repo)
+	return ghpr.base.clone(ctx, url)
+}
+
+// PushCommit creates a commit for the Worktree changes made by the UpdateFunc parameter
+// and pushes that branch to the remote origin server
+func (ghpr *GithubPR) PushCommit(ctx context.Context, branchName string, fn UpdateFunc) error {
+	return ghpr.base.pushCommit(ctx, branchName, fn)
 }
 
 // RaisePR creates a pull request from the sourceBranch (HEAD) to the targetBranch (base)
-func (ghpr *GithubPR) RaisePR(sourceBranch string, targetBranch string, title string, body string) error {
-	pr, _, err := ghpr.gitHubClient.PullRequests.Create(context.Background(),
+func (ghpr *GithubPR) RaisePR(ctx context.Context, sourceBranch string, targetBranch string, title string, body string) error {
+	pr, _, err := ghpr.gitHubClient.PullRequests.Create(ctx,
 		ghpr.owner, ghpr.repo,
 		&github.NewPullRequest{
 			Title: &title,
@@ -211,120 +460,107 @@ func (ghpr *GithubPR) RaisePR(sourceBranch string, targetBranch string, title st
 	return err
 }
 
-func (ghpr *GithubPR) waitForStatus(shaRef string, owner string, repo string, statusContext string) error {
-	c1 := make(chan error, 1)
-	go func() {
-		fmt.Printf("Waiting for %s to become mergeable\n", shaRef)
-		for {
-			time.Sleep(time.Second * 2)
-			statuses, _, err := ghpr.gitHubClient.Repositories.ListStatuses(context.Background(), owner, repo,
-				shaRef, &github.ListOptions{PerPage: 20})
-
-			if err != nil {
-				c1 <- err
-				return
-			}
-
-			if statuses != nil {
-				for i := 0; i < len(statuses); i++ {
-					context := statuses[i].GetContext()
-					state := statuses[i].GetState()
-
-					if context == statusContext {
-						if state == "success" {
-							c1 <- nil
-							return
-						}
-						if state == "failure" || state == "error" {
-							c1 <- errors.New("target status check is in a failed state, aborting")
-							return
-						}
-					}
-				}
-			}
-		}
-	}()
+// GetPRStatus returns the combined status of the PR's head commit, as
+// reported by GitHub's combined status API (e.g. "success", "pending",
+// "failure").
+func (ghpr *GithubPR) GetPRStatus(ctx context.Context) (string, error) {
+	pr, _, err := ghpr.gitHubClient.PullRequests.Get(ctx, ghpr.owner, ghpr.repo, ghpr.pr)
+	if err != nil {
+		return "", err
+	}
 
-	select {
-	case err := <-c1:
-		return err
-	case <-time.After(60 * time.Minute):
-		return errors.New("timed out waiting for PR to become mergeable")
+	combined, _, err := ghpr.gitHubClient.Repositories.GetCombinedStatus(ctx, ghpr.owner, ghpr.repo, *pr.Head.SHA, nil)
+	if err != nil {
+		return "", err
 	}
+
+	return combined.GetState(), nil
 }
 
-// WaitForPR waits until the raised PR passes the supplied status check. It returns
-// an error if a failed or errored state is encountered
-func (ghpr *GithubPR) WaitForPR(statusContext string) error {
-	pr, _, err := ghpr.gitHubClient.PullRequests.Get(context.Background(), ghpr.owner, ghpr.repo, ghpr.pr)
+// ListStatuses returns the individual commit statuses recorded against sha.
+func (ghpr *GithubPR) ListStatuses(ctx context.Context, sha string) ([]Status, error) {
+	statuses, _, err := ghpr.gitHubClient.Repositories.ListStatuses(ctx, ghpr.owner, ghpr.repo,
+		sha, &github.ListOptions{PerPage: 20})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	fmt.Printf("HEAD sha is %s\n", *pr.Head.SHA)
-	return ghpr.waitForStatus(*pr.Head.SHA, ghpr.owner, ghpr.repo, statusContext)
-
+	result := make([]Status, 0, len(statuses))
+	for _, s := range statuses {
+		result = append(result, Status{Context: s.GetContext(), State: s.GetState()})
+	}
+	return result, nil
 }
 
-// MergePR merges a PR, provided it is in a mergeable state, otherwise returning
-// an error
-func (ghpr *GithubPR) MergePR() error {
-	pr, _, err := ghpr.gitHubClient.PullRequests.Get(context.Background(), ghpr.owner, ghpr.repo, ghpr.pr)
+// Merge merges the PR using the given merge method ("merge", "squash" or
+// "rebase"), provided the PR is in a mergeable state, otherwise returning an
+// error. sha is asserted against the PR's current HEAD to guard against
+// merging a stale commit.
+func (ghpr *GithubPR) Merge(ctx context.Context, sha string, method string) error {
+	pr, _, err := ghpr.gitHubClient.PullRequests.Get(ctx, ghpr.owner, ghpr.repo, ghpr.pr)
 	if err != nil {
 		return err
 	}
 
-	if pr.Mergeable != nil && *pr.Mergeable {
-		merge, _, err := ghpr.gitHubClient.PullRequests.Merge(context.Background(), ghpr.owner, ghpr.repo, *pr.Number, "", &github.PullRequestOptions{MergeMethod: "merge"})
-		if err != nil {
-			return err
-		}
-		ghpr.mergeSHA = *merge.SHA
-	} else {
+	if pr.Mergeable == nil || !*pr.Mergeable {
 		return errors.New("PR is not mergeable")
 	}
+
+	merge, _, err := ghpr.gitHubClient.PullRequests.Merge(ctx, ghpr.owner, ghpr.repo, *pr.Number, "",
+		&github.PullRequestOptions{MergeMethod: method, SHA: sha})
+	if err != nil {
+		return err
+	}
+
+	ghpr.mergeSHA = *merge.SHA
 	return nil
 }
 
-// WaitForMergeCommit waits for the merge commit to receive a successful state
-// for the supplied status check. It returns an error if a failed or errored
-// state is encountered
-func (ghpr *GithubPR) WaitForMergeCommit(statusContext string) error {
-	return ghpr.waitForStatus(ghpr.mergeSHA, ghpr.owner, ghpr.repo, statusContext)
+// MergePR merges a PR via the default "merge" method, provided it is in a
+// mergeable state, otherwise returning an error
+func (ghpr *GithubPR) MergePR(ctx context.Context) error {
+	pr, _, err := ghpr.gitHubClient.PullRequests.Get(ctx, ghpr.owner, ghpr.repo, ghpr.pr)
+	if err != nil {
+		return err
+	}
+	if pr.Head.SHA == nil {
+		return errors.New("PR has no head SHA")
+	}
+	return ghpr.Merge(ctx, *pr.Head.SHA, "merge")
 }
 
 // Close removes the cloned repository from the filesystem
 func (ghpr *GithubPR) Close() error {
-	return os.RemoveAll(ghpr.path)
+	return ghpr.base.close()
 }
 
-func (ghpr *GithubPR) Create(branchName string, targetBranch string, prStatusContext string, masterStatusContext string, fn UpdateFunc) error {
-	err := ghpr.Clone()
+func (ghpr *GithubPR) Create(ctx context.Context, branchName string, targetBranch string, prWait WaitOptions, mergeWait WaitOptions, fn UpdateFunc) error {
+	err := ghpr.Clone(ctx)
 	defer ghpr.Close()
 	if err != nil {
 		return err
 	}
 
-	err = ghpr.PushCommit(branchName, fn)
+	err = ghpr.PushCommit(ctx, branchName, fn)
 	if err != nil {
 		return err
 	}
 
 	stuff := "test"
-	err = ghpr.RaisePR(branchName, targetBranch, stuff, "")
+	err = ghpr.RaisePR(ctx, branchName, targetBranch, stuff, "")
 	if err != nil {
 		return err
 	}
 
-	err = ghpr.WaitForPR(prStatusContext)
+	err = ghpr.WaitForPR(ctx, prWait)
 	if err != nil {
 		return err
 	}
 
-	err = ghpr.MergePR()
+	err = ghpr.MergePR(ctx)
 	if err != nil {
 		return err
 	}
 
-	return ghpr.WaitForMergeCommit(masterStatusContext)
+	return ghpr.WaitForMergeCommit(ctx, mergeWait)
 }
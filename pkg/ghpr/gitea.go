@@ -0,0 +1,131 @@
+package ghpr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/osfs"
+)
+
+// GiteaPR is a Forge implementation backed by a self-hosted Gitea instance.
+// baseURL is required since, unlike GitHub or GitLab, there is no single
+// well-known Gitea host.
+type GiteaPR struct {
+	base
+	giteaClient *gitea.Client
+	pr          int64
+}
+
+// MakeGiteaPR creates a new GiteaPR with all the necessary state to clone,
+// commit, raise a PR and merge, against the Gitea instance at baseURL.
+func MakeGiteaPR(baseURL string, repoName string, creds Credentials, opts ...Option) (*GiteaPR, error) {
+	return MakeGiteaPRWithAuth(baseURL, repoName, creds.toAuthMethod(), opts...)
+}
+
+// MakeGiteaPRWithAuth creates a new GiteaPR authenticating with auth, for
+// callers that need SSH-key, SSH-agent or anonymous git transport instead of
+// a plain access token.
+func MakeGiteaPRWithAuth(baseURL string, repoName string, auth AuthMethod, opts ...Option) (*GiteaPR, error) {
+	fs := osfs.New(".")
+	return makeGiteaPR(baseURL, repoName, auth, &fs, realGoGit{}, opts...)
+}
+
+func makeGiteaPR(baseURL string, repoName string, auth AuthMethod, fs *billy.Filesystem, gogit goGit, opts ...Option) (*GiteaPR, error) {
+	b, err := newBase(repoName, auth, fs, gogit, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := gitea.NewClient(baseURL, gitea.SetToken(auth.Token))
+	if err != nil {
+		return nil, err
+	}
+	b.baseURL = baseURL
+
+	return &GiteaPR{
+		base:        b,
+		giteaClient: client,
+	}, nil
+}
+
+// Clone shallow clones the Gitea repository
+func (g *GiteaPR) Clone(ctx context.Context) error {
+	url := fmt.Sprintf("%s/%s/%s.git", g.baseURL, g.owner, g.repo)
+	return g.base.clone(ctx, url)
+}
+
+// PushCommit creates a commit for the Worktree changes made by the
+// UpdateFunc parameter and pushes that branch to the remote origin server
+func (g *GiteaPR) PushCommit(ctx context.Context, branchName string, fn UpdateFunc) error {
+	return g.base.pushCommit(ctx, branchName, fn)
+}
+
+// RaisePR opens a Gitea pull request from sourceBranch (HEAD) to
+// targetBranch (base). The Gitea SDK does not accept a context per call, so
+// ctx is applied to the client for the duration of this request.
+func (g *GiteaPR) RaisePR(ctx context.Context, sourceBranch string, targetBranch string, title string, body string) error {
+	g.giteaClient.SetContext(ctx)
+	pr, _, err := g.giteaClient.CreatePullRequest(g.owner, g.repo, gitea.CreatePullRequestOption{
+		Head:  sourceBranch,
+		Base:  targetBranch,
+		Title: title,
+		Body:  body,
+	})
+	if err != nil {
+		return err
+	}
+
+	g.pr = pr.Index
+	return nil
+}
+
+// GetPRStatus returns the combined commit status of the PR's head commit.
+func (g *GiteaPR) GetPRStatus(ctx context.Context) (string, error) {
+	g.giteaClient.SetContext(ctx)
+	pr, _, err := g.giteaClient.GetPullRequest(g.owner, g.repo, g.pr)
+	if err != nil {
+		return "", err
+	}
+	if pr.Head == nil {
+		return "", errors.New("pull request has no head commit yet")
+	}
+
+	combined, _, err := g.giteaClient.GetCombinedCommitStatusByRef(g.owner, g.repo, pr.Head.Sha)
+	if err != nil {
+		return "", err
+	}
+	return string(combined.State), nil
+}
+
+// ListStatuses returns the individual commit statuses recorded against sha.
+func (g *GiteaPR) ListStatuses(ctx context.Context, sha string) ([]Status, error) {
+	g.giteaClient.SetContext(ctx)
+	statuses, _, err := g.giteaClient.ListStatuses(g.owner, g.repo, sha, gitea.ListStatusesOption{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Status, 0, len(statuses))
+	for _, s := range statuses {
+		result = append(result, Status{Context: s.Context, State: string(s.State)})
+	}
+	return result, nil
+}
+
+// Merge merges the PR using the given merge method ("merge", "squash" or
+// "rebase").
+func (g *GiteaPR) Merge(ctx context.Context, sha string, method string) error {
+	g.giteaClient.SetContext(ctx)
+	_, err := g.giteaClient.MergePullRequest(g.owner, g.repo, g.pr, gitea.MergePullRequestOption{
+		Style: gitea.MergeStyle(method),
+	})
+	return err
+}
+
+// Close removes the cloned repository from the filesystem
+func (g *GiteaPR) Close() error {
+	return g.base.close()
+}
@@ -0,0 +1,136 @@
+package ghpr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/xanzy/go-gitlab"
+)
+
+// GitlabPR is a Forge implementation backed by GitLab merge requests and
+// pipeline statuses. It mirrors GithubPR's shape, swapping the go-github
+// client for a go-gitlab one.
+type GitlabPR struct {
+	base
+	gitlabClient *gitlab.Client
+	mr           int
+}
+
+// MakeGitlabPR creates a new GitlabPR with all the necessary state to clone,
+// commit, raise a merge request and merge. creds.Token is used as a GitLab
+// personal access token. The GitLab API client uses the token alone;
+// creds.Username is only the HTTP basic-auth username for clone and push.
+func MakeGitlabPR(repoName string, creds Credentials, opts ...Option) (*GitlabPR, error) {
+	return MakeGitlabPRWithAuth(repoName, creds.toAuthMethod(), opts...)
+}
+
+// MakeGitlabPRWithAuth creates a new GitlabPR authenticating with auth, for
+// callers that need SSH-key, SSH-agent or anonymous git transport instead of
+// a plain access token.
+func MakeGitlabPRWithAuth(repoName string, auth AuthMethod, opts ...Option) (*GitlabPR, error) {
+	fs := osfs.New(".")
+	return makeGitlabPR(repoName, auth, &fs, realGoGit{}, opts...)
+}
+
+func makeGitlabPR(repoName string, auth AuthMethod, fs *billy.Filesystem, gogit goGit, opts ...Option) (*GitlabPR, error) {
+	b, err := newBase(repoName, auth, fs, gogit, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	glClient, err := gitlab.NewClient(auth.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GitlabPR{
+		base:         b,
+		gitlabClient: glClient,
+	}, nil
+}
+
+// projectID is the "owner/repo"-style path GitLab's API accepts in place of
+// a numeric project ID.
+func (g *GitlabPR) projectID() string {
+	return fmt.Sprintf("%s/%s", g.owner, g.repo)
+}
+
+// Clone shallow clones the GitLab project
+func (g *GitlabPR) Clone(ctx context.Context) error {
+	url := fmt.Sprintf("https://gitlab.com/%s.git", g.projectID())
+	return g.base.clone(ctx, url)
+}
+
+// PushCommit creates a commit for the Worktree changes made by the
+// UpdateFunc parameter and pushes that branch to the remote origin server
+func (g *GitlabPR) PushCommit(ctx context.Context, branchName string, fn UpdateFunc) error {
+	return g.base.pushCommit(ctx, branchName, fn)
+}
+
+// RaisePR opens a GitLab merge request from sourceBranch (HEAD) to
+// targetBranch (base).
+func (g *GitlabPR) RaisePR(ctx context.Context, sourceBranch string, targetBranch string, title string, body string) error {
+	mr, _, err := g.gitlabClient.MergeRequests.CreateMergeRequest(g.projectID(), &gitlab.CreateMergeRequestOptions{
+		Title:        &title,
+		Description:  &body,
+		SourceBranch: &sourceBranch,
+		TargetBranch: &targetBranch,
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	g.mr = mr.IID
+	return nil
+}
+
+// GetPRStatus returns the aggregate status of the merge request's pipeline.
+func (g *GitlabPR) GetPRStatus(ctx context.Context) (string, error) {
+	mr, _, err := g.gitlabClient.MergeRequests.GetMergeRequest(g.projectID(), g.mr, nil, gitlab.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	if mr.HeadPipeline == nil {
+		return "", errors.New("merge request has no pipeline yet")
+	}
+	return mr.HeadPipeline.Status, nil
+}
+
+// ListStatuses returns the individual pipeline job statuses recorded against
+// sha, normalized to the shared Status type.
+func (g *GitlabPR) ListStatuses(ctx context.Context, sha string) ([]Status, error) {
+	commitStatuses, _, err := g.gitlabClient.Commits.GetCommitStatuses(g.projectID(), sha, nil, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Status, 0, len(commitStatuses))
+	for _, s := range commitStatuses {
+		result = append(result, Status{Context: s.Name, State: s.Status})
+	}
+	return result, nil
+}
+
+// Merge merges the merge request using the given merge method. GitLab does
+// not distinguish merge methods per-request the way GitHub does, so method
+// only controls whether commits are squashed.
+func (g *GitlabPR) Merge(ctx context.Context, sha string, method string) error {
+	opts := &gitlab.AcceptMergeRequestOptions{
+		SHA: &sha,
+	}
+	if method == "squash" {
+		squash := true
+		opts.Squash = &squash
+	}
+
+	_, _, err := g.gitlabClient.MergeRequests.AcceptMergeRequest(g.projectID(), g.mr, opts, gitlab.WithContext(ctx))
+	return err
+}
+
+// Close removes the cloned repository from the filesystem
+func (g *GitlabPR) Close() error {
+	return g.base.close()
+}
@@ -0,0 +1,277 @@
+package ghpr
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Default retry schedule used by RetryPolicy when a field is left zero.
+const (
+	DefaultRetryMaxAttempts     = 1
+	DefaultRetryInitialInterval = 5 * time.Second
+	DefaultRetryMaxInterval     = 60 * time.Second
+	DefaultRetryBackoffFactor   = 2.0
+)
+
+// RetryPolicy configures how a Campaign retries a repo whose run failed.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of tries per repo, including the
+	// first. Zero means DefaultRetryMaxAttempts (no retries).
+	MaxAttempts int
+	// InitialInterval is the delay before the first retry. Zero means
+	// DefaultRetryInitialInterval.
+	InitialInterval time.Duration
+	// MaxInterval caps the exponential backoff applied between retries.
+	// Zero means DefaultRetryMaxInterval.
+	MaxInterval time.Duration
+	// BackoffFactor multiplies the interval after each failed attempt.
+	// Zero means DefaultRetryBackoffFactor.
+	BackoffFactor float64
+}
+
+// withDefaults fills any zero-valued field with its documented default.
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts == 0 {
+		p.MaxAttempts = DefaultRetryMaxAttempts
+	}
+	if p.InitialInterval == 0 {
+		p.InitialInterval = DefaultRetryInitialInterval
+	}
+	if p.MaxInterval == 0 {
+		p.MaxInterval = DefaultRetryMaxInterval
+	}
+	if p.BackoffFactor == 0 {
+		p.BackoffFactor = DefaultRetryBackoffFactor
+	}
+	return p
+}
+
+// RunOptions configures how a Campaign runs across its repo list.
+type RunOptions struct {
+	// Parallelism is the number of repos processed concurrently. Zero or
+	// negative means 1 (sequential).
+	Parallelism int
+	// RetryPolicy governs per-repo retries on failure.
+	RetryPolicy RetryPolicy
+	// ContinueOnError keeps the Campaign running the remaining repos after
+	// one fails. When false, the first repo to exhaust its retries cancels
+	// every repo still queued or in flight.
+	ContinueOnError bool
+	// DryRun clones each repo and runs UpdateFunc against its worktree, but
+	// skips PushCommit/RaisePR/MergePR entirely, printing a diff of the
+	// would-be commit instead.
+	DryRun bool
+}
+
+// RepoResult is the outcome of running a Campaign against a single repo.
+type RepoResult struct {
+	RepoName string
+	// Status is one of "merged", "raised" (PR opened but not merged),
+	// "dry-run" or "failed" (the run failed before a PR was opened). Err is
+	// set whenever the run failed, whatever its Status.
+	Status   string
+	PRURL    string
+	Err      error
+	Duration time.Duration
+}
+
+// Report is the aggregate result of a Campaign run, one RepoResult per repo
+// that was run, in the order each one finished. Repos abandoned after a
+// failure (see RunOptions.ContinueOnError) have no RepoResult.
+type Report struct {
+	Results []RepoResult
+}
+
+// Failed returns the subset of Results whose Err is non-nil.
+func (r Report) Failed() []RepoResult {
+	var failed []RepoResult
+	for _, res := range r.Results {
+		if res.Err != nil {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// Campaign drives the same UpdateFunc across many GitHub repos, opening (and
+// optionally merging) a pull request in each, the way Create does for one
+// repo.
+type Campaign struct {
+	Creds        Credentials
+	BranchName   string
+	TargetBranch string
+	Title        string
+	Body         string
+	Update       UpdateFunc
+	PRWait       WaitOptions
+	MergeWait    WaitOptions
+	Opts         RunOptions
+}
+
+// NewCampaign returns a Campaign ready to Run against a list of repos.
+// prWait and mergeWait must each require at least one context: with none,
+// WaitForPR/WaitForMergeCommit return an error, so every repo would fail
+// right after its PR was raised. DryRun skips pushing and waiting entirely.
+func NewCampaign(creds Credentials, branchName string, targetBranch string, title string, body string, fn UpdateFunc, prWait WaitOptions, mergeWait WaitOptions, opts RunOptions) (Campaign, error) {
+	if len(prWait.Contexts) == 0 {
+		return Campaign{}, fmt.Errorf("ghpr: prWait must require at least one context")
+	}
+	if len(mergeWait.Contexts) == 0 {
+		return Campaign{}, fmt.Errorf("ghpr: mergeWait must require at least one context")
+	}
+
+	return Campaign{
+		Creds:        creds,
+		BranchName:   branchName,
+		TargetBranch: targetBranch,
+		Title:        title,
+		Body:         body,
+		Update:       fn,
+		PRWait:       prWait,
+		MergeWait:    mergeWait,
+		Opts:         opts,
+	}, nil
+}
+
+// Run drives the Campaign across repoNames, processing up to
+// c.Opts.Parallelism of them at a time, and returns a Report once every repo
+// has either finished or been abandoned because of ContinueOnError.
+func (c Campaign) Run(ctx context.Context, repoNames []string) Report {
+	parallelism := c.Opts.Parallelism
+	if parallelism <= 0 {
+		parallelism = 1
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan string)
+	results := make(chan RepoResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for repoName := range jobs {
+				res := c.runOne(runCtx, repoName)
+				results <- res
+				if res.Err != nil && !c.Opts.ContinueOnError {
+					cancel()
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, repoName := range repoNames {
+			select {
+			case jobs <- repoName:
+			case <-runCtx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var report Report
+	for res := range results {
+		report.Results = append(report.Results, res)
+	}
+	return report
+}
+
+// runOne runs the Campaign against a single repo, retrying according to
+// c.Opts.RetryPolicy until it succeeds, the retries are exhausted, or ctx is
+// cancelled.
+func (c Campaign) runOne(ctx context.Context, repoName string) RepoResult {
+	start := time.Now()
+	policy := c.Opts.RetryPolicy.withDefaults()
+	interval := policy.InitialInterval
+
+	var status, prURL string
+	var err error
+	for attempt := 1; attempt <= policy.MaxAttempts; attempt++ {
+		status, prURL, err = c.attempt(ctx, repoName)
+		if err == nil {
+			break
+		}
+		if attempt == policy.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			attempt = policy.MaxAttempts
+		case <-time.After(interval):
+		}
+
+		interval = time.Duration(float64(interval) * policy.BackoffFactor)
+		if interval > policy.MaxInterval {
+			interval = policy.MaxInterval
+		}
+	}
+
+	if err != nil && status == "" {
+		status = "failed"
+	}
+	return RepoResult{
+		RepoName: repoName,
+		Status:   status,
+		PRURL:    prURL,
+		Err:      err,
+		Duration: time.Since(start),
+	}
+}
+
+// attempt makes a single Clone→PushCommit→RaisePR(→WaitForPR→MergePR) pass
+// at repoName, or, in DryRun mode, a Clone and a local commit whose diff is
+// printed instead of pushed.
+func (c Campaign) attempt(ctx context.Context, repoName string) (status string, prURL string, err error) {
+	ghpr, err := MakeGithubPR(repoName, c.Creds)
+	if err != nil {
+		return "", "", err
+	}
+	defer ghpr.Close()
+
+	if err := ghpr.Clone(ctx); err != nil {
+		return "", "", err
+	}
+
+	if c.Opts.DryRun {
+		diff, err := ghpr.base.dryRunCommit(c.BranchName, c.Update)
+		if err != nil {
+			return "", "", err
+		}
+		fmt.Printf("--- dry run diff for %s ---\n%s\n", repoName, diff)
+		return "dry-run", "", nil
+	}
+
+	if err := ghpr.PushCommit(ctx, c.BranchName, c.Update); err != nil {
+		return "", "", err
+	}
+	if err := ghpr.RaisePR(ctx, c.BranchName, c.TargetBranch, c.Title, c.Body); err != nil {
+		return "", "", err
+	}
+	prURL = fmt.Sprintf("https://github.com/%s/pull/%d", repoName, ghpr.pr)
+
+	if err := ghpr.WaitForPR(ctx, c.PRWait); err != nil {
+		return "raised", prURL, err
+	}
+	if err := ghpr.MergePR(ctx); err != nil {
+		return "raised", prURL, err
+	}
+	if err := ghpr.WaitForMergeCommit(ctx, c.MergeWait); err != nil {
+		return "merged", prURL, err
+	}
+
+	return "merged", prURL, nil
+}
@@ -0,0 +1,164 @@
+package ghpr
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/ssh"
+)
+
+// Signer cryptographically signs the commits PushCommit creates, so they
+// satisfy branch protection rules that require signed commits.
+//
+// GPGSigner plugs into go-git's native CommitOptions.SignKey support.
+// SSHSigner has no such native support in go-git, so PushCommit instead
+// signs the commit's canonical encoding out-of-band and attaches the result
+// to the commit's PGPSignature field itself, the same mechanism git's own
+// gpg.format=ssh commits use.
+type Signer interface {
+	// openpgpEntity returns the OpenPGP entity to hand to go-git's
+	// CommitOptions.SignKey, or nil if this Signer signs out-of-band (see
+	// signDetached).
+	openpgpEntity() *openpgp.Entity
+
+	// signDetached signs commitData, the canonical encoding of a commit
+	// that does not yet carry a signature, and returns the text to store
+	// in that commit's PGPSignature field. It is only called when
+	// openpgpEntity returns nil.
+	signDetached(commitData []byte) (string, error)
+}
+
+// GPGSigner signs commits with an OpenPGP private key, via go-git's native
+// CommitOptions.SignKey support.
+type GPGSigner struct {
+	entity *openpgp.Entity
+}
+
+// NewGPGSigner wraps an already-loaded OpenPGP entity.
+func NewGPGSigner(entity *openpgp.Entity) *GPGSigner {
+	return &GPGSigner{entity: entity}
+}
+
+// LoadGPGSigner reads an armored OpenPGP private key from keyring and
+// decrypts it with passphrase, which may be nil if the key is unencrypted.
+func LoadGPGSigner(keyring io.Reader, passphrase []byte) (*GPGSigner, error) {
+	entityList, err := openpgp.ReadArmoredKeyRing(keyring)
+	if err != nil {
+		return nil, err
+	}
+	if len(entityList) == 0 {
+		return nil, fmt.Errorf("ghpr: keyring contained no entities")
+	}
+
+	entity := entityList[0]
+	if entity.PrivateKey != nil && entity.PrivateKey.Encrypted {
+		if err := entity.PrivateKey.Decrypt(passphrase); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewGPGSigner(entity), nil
+}
+
+func (s *GPGSigner) openpgpEntity() *openpgp.Entity { return s.entity }
+
+func (s *GPGSigner) signDetached(commitData []byte) (string, error) {
+	return "", fmt.Errorf("ghpr: GPGSigner signs via go-git's CommitOptions.SignKey, not out-of-band")
+}
+
+// SSHSigner signs commits with an SSH key, producing the SSHSIG-format
+// signature recognized by forges that support git's gpg.format=ssh.
+type SSHSigner struct {
+	signer ssh.Signer
+}
+
+// NewSSHSigner wraps an already-loaded ssh.Signer, e.g. one returned by
+// ssh.ParsePrivateKey or an agent.Agent's Signers().
+func NewSSHSigner(signer ssh.Signer) *SSHSigner {
+	return &SSHSigner{signer: signer}
+}
+
+func (s *SSHSigner) openpgpEntity() *openpgp.Entity { return nil }
+
+// sshSigMagic, sshSigNamespace and sshSigHashAlgo follow the SSHSIG format
+// defined by OpenSSH's PROTOCOL.sshsig: a signature does not cover the
+// message directly, but a wrapper binding it to a namespace (git's own
+// signed objects use "git") and the name of the hash algorithm used.
+const (
+	sshSigMagic     = "SSHSIG"
+	sshSigVersion   = 1
+	sshSigNamespace = "git"
+	sshSigHashAlgo  = "sha512"
+)
+
+func (s *SSHSigner) signDetached(commitData []byte) (string, error) {
+	hashed := sha512.Sum512(commitData)
+	toSign := sshSigSignedData(sshSigNamespace, sshSigHashAlgo, hashed[:])
+
+	sig, err := s.signer.Sign(rand.Reader, toSign)
+	if err != nil {
+		return "", err
+	}
+
+	var blob bytes.Buffer
+	blob.WriteString(sshSigMagic)
+	writeUint32(&blob, sshSigVersion)
+	writeSSHString(&blob, s.signer.PublicKey().Marshal())
+	writeSSHString(&blob, []byte(sshSigNamespace))
+	writeSSHString(&blob, nil) // reserved
+	writeSSHString(&blob, []byte(sshSigHashAlgo))
+	writeSSHString(&blob, ssh.Marshal(sig))
+
+	return encodeSSHSignature(blob.Bytes()), nil
+}
+
+// sshSigSignedData builds the blob that is actually passed to the signer,
+// per PROTOCOL.sshsig: the literal magic preamble followed by the
+// namespace, a reserved field, the hash algorithm name and the digest of
+// the message, each as an SSH wire-format string.
+func sshSigSignedData(namespace string, hashAlgo string, hashed []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(sshSigMagic)
+	writeSSHString(&buf, []byte(namespace))
+	writeSSHString(&buf, nil) // reserved
+	writeSSHString(&buf, []byte(hashAlgo))
+	writeSSHString(&buf, hashed)
+	return buf.Bytes()
+}
+
+func writeUint32(buf *bytes.Buffer, v uint32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+	buf.Write(b[:])
+}
+
+// writeSSHString appends b to buf as an SSH wire-format string: a
+// four-byte big-endian length followed by the raw bytes.
+func writeSSHString(buf *bytes.Buffer, b []byte) {
+	writeUint32(buf, uint32(len(b)))
+	buf.Write(b)
+}
+
+// encodeSSHSignature wraps an SSHSIG blob in the PEM-like armor git itself
+// writes for gpg.format=ssh commits.
+func encodeSSHSignature(blob []byte) string {
+	encoded := base64.StdEncoding.EncodeToString(blob)
+
+	out := "-----BEGIN SSH SIGNATURE-----\n"
+	for len(encoded) > 0 {
+		n := 76
+		if n > len(encoded) {
+			n = len(encoded)
+		}
+		out += encoded[:n] + "\n"
+		encoded = encoded[n:]
+	}
+	out += "-----END SSH SIGNATURE-----\n"
+	return out
+}
@@ -0,0 +1,218 @@
+package ghpr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/github"
+)
+
+// Default backoff schedule used by WaitOptions when a field is left zero.
+const (
+	DefaultWaitTimeout     = 60 * time.Minute
+	DefaultInitialInterval = 2 * time.Second
+	DefaultMaxInterval     = 30 * time.Second
+	DefaultBackoffFactor   = 2.0
+)
+
+// WaitOptions configures WaitForPR and WaitForMergeCommit's polling loop.
+// All of Contexts must report success before the wait succeeds; any single
+// failure, error, cancellation or timeout short-circuits it.
+type WaitOptions struct {
+	// Contexts lists the status contexts and/or check-run names that must
+	// all succeed.
+	Contexts []string
+	// Timeout bounds the overall wait. Zero means DefaultWaitTimeout.
+	Timeout time.Duration
+	// InitialInterval is the delay before the first re-check. Zero means
+	// DefaultInitialInterval.
+	InitialInterval time.Duration
+	// MaxInterval caps the exponential backoff applied between polls. Zero
+	// means DefaultMaxInterval.
+	MaxInterval time.Duration
+	// BackoffFactor multiplies the interval after each unsuccessful poll.
+	// Zero means DefaultBackoffFactor.
+	BackoffFactor float64
+	// IncludeChecks also polls the GitHub Checks API (check-runs) in
+	// addition to the legacy Statuses API, so required checks backed by
+	// GitHub Actions are recognized.
+	IncludeChecks bool
+}
+
+// NewWaitOptions returns a WaitOptions requiring all of contexts to succeed,
+// using the module's default backoff schedule.
+func NewWaitOptions(contexts ...string) WaitOptions {
+	return WaitOptions{Contexts: contexts}
+}
+
+// withDefaults fills any zero-valued field with its documented default.
+func (o WaitOptions) withDefaults() WaitOptions {
+	if o.Timeout == 0 {
+		o.Timeout = DefaultWaitTimeout
+	}
+	if o.InitialInterval == 0 {
+		o.InitialInterval = DefaultInitialInterval
+	}
+	if o.MaxInterval == 0 {
+		o.MaxInterval = DefaultMaxInterval
+	}
+	if o.BackoffFactor == 0 {
+		o.BackoffFactor = DefaultBackoffFactor
+	}
+	return o
+}
+
+// WaitForPR waits until the raised PR's head commit satisfies opts. It
+// returns an error if a failed/errored/cancelled/timed-out check is
+// encountered, or if the wait itself times out or ctx is cancelled first.
+func (ghpr *GithubPR) WaitForPR(ctx context.Context, opts WaitOptions) error {
+	pr, _, err := ghpr.gitHubClient.PullRequests.Get(ctx, ghpr.owner, ghpr.repo, ghpr.pr)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("HEAD sha is %s\n", *pr.Head.SHA)
+	return ghpr.waitForStatus(ctx, *pr.Head.SHA, opts)
+}
+
+// WaitForMergeCommit waits until the merge commit satisfies opts. It returns
+// an error if a failed/errored/cancelled/timed-out check is encountered, or
+// if the wait itself times out or ctx is cancelled first.
+func (ghpr *GithubPR) WaitForMergeCommit(ctx context.Context, opts WaitOptions) error {
+	return ghpr.waitForStatus(ctx, ghpr.mergeSHA, opts)
+}
+
+// waitForStatus polls sha's statuses (and, if requested, check-runs) with
+// exponential backoff until every context in opts.Contexts reports success,
+// one of them fails, or opts.Timeout elapses.
+func (ghpr *GithubPR) waitForStatus(ctx context.Context, sha string, opts WaitOptions) error {
+	if len(opts.Contexts) == 0 {
+		return errors.New("ghpr: WaitOptions.Contexts must name at least one context to wait for")
+	}
+	opts = opts.withDefaults()
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	fmt.Printf("Waiting for %s to become mergeable\n", sha)
+	interval := opts.InitialInterval
+
+	for {
+		satisfied, err := ghpr.contextsSatisfied(ctx, sha, opts)
+		if err != nil {
+			return err
+		}
+		if satisfied {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return errors.New("timed out waiting for PR to become mergeable")
+			}
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+
+		interval = time.Duration(float64(interval) * opts.BackoffFactor)
+		if interval > opts.MaxInterval {
+			interval = opts.MaxInterval
+		}
+	}
+}
+
+// contextsSatisfied reports whether every context in opts.Contexts has
+// reached a successful state for sha. It returns a nil error with false on
+// a transient condition (not yet reported, or rate-limited) that's worth
+// retrying, and a non-nil error only for a hard failure.
+func (ghpr *GithubPR) contextsSatisfied(ctx context.Context, sha string, opts WaitOptions) (bool, error) {
+	statuses, err := ghpr.ListStatuses(ctx, sha)
+	if err != nil {
+		if waitOutRateLimit(ctx, err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if opts.IncludeChecks {
+		checks, err := ghpr.listCheckRuns(ctx, sha)
+		if err != nil {
+			if waitOutRateLimit(ctx, err) {
+				return false, nil
+			}
+			return false, err
+		}
+		statuses = append(statuses, checks...)
+	}
+
+	// GitHub lists statuses newest first, so the first entry seen for a
+	// context is its current state.
+	states := make(map[string]string, len(statuses))
+	for _, s := range statuses {
+		if _, seen := states[s.Context]; !seen {
+			states[s.Context] = s.State
+		}
+	}
+
+	for _, want := range opts.Contexts {
+		state, reported := states[want]
+		if !reported {
+			return false, nil
+		}
+
+		switch state {
+		case "success", "neutral":
+		case "failure", "error", "cancelled", "timed_out":
+			return false, fmt.Errorf("status check %q is in a failed state (%s), aborting", want, state)
+		default:
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// listCheckRuns returns the GitHub Actions check-runs recorded against sha,
+// normalized to the shared Status type. A check-run's State is its
+// conclusion once completed (e.g. "success", "failure"), or its in-progress
+// status otherwise (e.g. "queued", "in_progress").
+func (ghpr *GithubPR) listCheckRuns(ctx context.Context, sha string) ([]Status, error) {
+	checks, _, err := ghpr.gitHubClient.Checks.ListCheckRunsForRef(ctx, ghpr.owner, ghpr.repo, sha, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Status, 0, len(checks.CheckRuns))
+	for _, run := range checks.CheckRuns {
+		state := run.GetStatus()
+		if state == "completed" {
+			state = run.GetConclusion()
+		}
+		result = append(result, Status{Context: run.GetName(), State: state})
+	}
+	return result, nil
+}
+
+// waitOutRateLimit checks whether err is a GitHub rate-limit error and, if
+// so, sleeps until the reported reset time (or ctx is done) before
+// reporting true so the caller retries instead of failing the wait.
+func waitOutRateLimit(ctx context.Context, err error) bool {
+	rateLimitErr, ok := err.(*github.RateLimitError)
+	if !ok {
+		return false
+	}
+
+	wait := time.Until(rateLimitErr.Rate.Reset.Time)
+	if wait < 0 {
+		wait = 0
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(wait):
+	}
+	return true
+}